@@ -0,0 +1,102 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// XgressDetail is the live state Registry.Inspect surfaces for a single session, assembled from whatever the
+// running channelPeekHandler/xgressPeekHandler have observed for that session's link. It exists to let
+// operators diagnose a stuck circuit without turning on tracing.
+type XgressDetail struct {
+	SessionId string
+	LinkId    string
+
+	TxSeq uint64
+	RxSeq uint64
+	Flags uint32
+
+	LastTxAt time.Time
+	LastRxAt time.Time
+
+	TxBytesRate1m float64
+	RxBytesRate1m float64
+	TxMsgRate1m   float64
+	RxMsgRate1m   float64
+
+	MsgSizeP50 float64
+	MsgSizeP99 float64
+
+	UsageBucket uint64
+}
+
+// Inspectable is implemented by a peek handler that tracks enough per-session state to answer an inspection
+// query, namely channelPeekHandler and xgressPeekHandler.
+type Inspectable interface {
+	Inspect(sessionId string) (XgressDetail, bool)
+}
+
+// SessionInspector aggregates the Inspectables created for a router's links and xgress instances, so a single
+// Registry.Inspect(sessionId) call can find whichever one is currently tracking a given session.
+type SessionInspector struct {
+	lock         sync.Mutex
+	inspectables []Inspectable
+}
+
+// NewSessionInspector creates an empty SessionInspector. Peek handlers register themselves via Register as they
+// are created.
+func NewSessionInspector() *SessionInspector {
+	return &SessionInspector{}
+}
+
+// Register adds an Inspectable to the set consulted by Inspect.
+func (s *SessionInspector) Register(inspectable Inspectable) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.inspectables = append(s.inspectables, inspectable)
+}
+
+// Unregister removes an Inspectable previously added via Register, so a closed peek handler stops being
+// consulted (and becomes eligible for garbage collection) instead of accumulating for the life of the process.
+func (s *SessionInspector) Unregister(inspectable Inspectable) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i, existing := range s.inspectables {
+		if existing == inspectable {
+			s.inspectables = append(s.inspectables[:i], s.inspectables[i+1:]...)
+			return
+		}
+	}
+}
+
+// Inspect looks up the live XgressDetail for sessionId across every registered Inspectable, returning the first
+// match.
+func (s *SessionInspector) Inspect(sessionId string) (XgressDetail, bool) {
+	s.lock.Lock()
+	inspectables := make([]Inspectable, len(s.inspectables))
+	copy(inspectables, s.inspectables)
+	s.lock.Unlock()
+
+	for _, inspectable := range inspectables {
+		if detail, found := inspectable.Inspect(sessionId); found {
+			return detail, true
+		}
+	}
+	return XgressDetail{}, false
+}
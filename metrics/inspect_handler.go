@@ -0,0 +1,46 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const inspectCircuitPath = "/inspect/circuit/"
+
+// NewInspectHandler serves live per-session XgressDetail as JSON at /inspect/circuit/{sessionId}, backed by a
+// SessionInspector.
+func NewInspectHandler(inspector *SessionInspector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionId := strings.TrimPrefix(r.URL.Path, inspectCircuitPath)
+		if sessionId == "" {
+			http.Error(w, "missing session id", http.StatusBadRequest)
+			return
+		}
+
+		detail, found := inspector.Inspect(sessionId)
+		if !found {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(detail)
+	})
+}
@@ -0,0 +1,96 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/netfoundry/ziti-foundation/channel2"
+)
+
+// latencyProbeHeaderKey is the reserved channel2 header slot used to carry a latency probe's correlation id.
+const latencyProbeHeaderKey = -100
+
+// maxPendingLatencyProbes bounds how many in-flight probes a latencyProbes tracks at once, so a peer that never
+// answers a probe can't grow this state unbounded.
+const maxPendingLatencyProbes = 1024
+
+// latencyProbes correlates outgoing latency probes with their replies using a local id->send-time table, so
+// round-trip latency is measured entirely against this process's own clock. This avoids comparing timestamps
+// across two peers' unsynchronized wall clocks, which would produce meaningless (and sometimes negative) deltas.
+// Only non-payload (control/heartbeat) messages are probed, to avoid adding bytes to every payload on the wire.
+type latencyProbes struct {
+	lock    sync.Mutex
+	nextId  uint64
+	pending map[uint64]time.Time
+}
+
+func newLatencyProbes() *latencyProbes {
+	return &latencyProbes{pending: make(map[uint64]time.Time)}
+}
+
+// stamp assigns a new correlation id, records the current send time against it, and writes the id into msg's
+// headers so it can be read back by resolve once the peer's reply arrives.
+func (p *latencyProbes) stamp(msg *channel2.Message) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.nextId++
+	id := p.nextId
+	p.pending[id] = time.Now()
+
+	if len(p.pending) > maxPendingLatencyProbes {
+		p.evictOldestLocked()
+	}
+
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, id)
+	msg.Headers[latencyProbeHeaderKey] = buf
+}
+
+func (p *latencyProbes) evictOldestLocked() {
+	var oldestId uint64
+	var oldestAt time.Time
+	for id, at := range p.pending {
+		if oldestAt.IsZero() || at.Before(oldestAt) {
+			oldestId, oldestAt = id, at
+		}
+	}
+	delete(p.pending, oldestId)
+}
+
+// resolve looks up the correlation id carried in msg's headers and, if it matches a still-pending probe, returns
+// the elapsed time since it was stamped.
+func (p *latencyProbes) resolve(msg *channel2.Message) (time.Duration, bool) {
+	buf, found := msg.Headers[latencyProbeHeaderKey]
+	if !found || len(buf) != 8 {
+		return 0, false
+	}
+	id := binary.BigEndian.Uint64(buf)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	sentAt, found := p.pending[id]
+	if !found {
+		return 0, false
+	}
+	delete(p.pending, id)
+	return time.Since(sentAt), true
+}
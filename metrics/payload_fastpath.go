@@ -0,0 +1,47 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import "encoding/binary"
+
+// fastPayloadHeader is the subset of a ContentTypePayloadType message body that usage tracking actually needs:
+// which session it belongs to, and how much data it carries. It mirrors the fixed-offset framing used by
+// low-overhead links (session id length, session id, data length) so it can be read without allocating a full
+// xgress.Payload.
+type fastPayloadHeader struct {
+	SessionId string
+	DataLen   int
+}
+
+// decodeFastPayloadHeader reads the session id and data length out of a payload message body at their fixed
+// offsets, skipping everything else. It returns false if the body is too short to hold a well-formed header, in
+// which case callers should fall back to the full xgress.UnmarshallPayload path.
+func decodeFastPayloadHeader(body []byte) (fastPayloadHeader, bool) {
+	if len(body) < 4 {
+		return fastPayloadHeader{}, false
+	}
+	sessionIdLen := int(binary.BigEndian.Uint32(body[0:4]))
+	sessionIdEnd := 4 + sessionIdLen
+	// sessionIdLen < 0 only happens when int is 32-bit and the on-wire length exceeds 1<<31; guard it explicitly
+	// rather than relying on the length check below, since sessionIdEnd itself would already have wrapped negative.
+	if sessionIdLen < 0 || len(body) < sessionIdEnd+4 {
+		return fastPayloadHeader{}, false
+	}
+	sessionId := string(body[4:sessionIdEnd])
+	dataLen := int(binary.BigEndian.Uint32(body[sessionIdEnd : sessionIdEnd+4]))
+	return fastPayloadHeader{SessionId: sessionId, DataLen: dataLen}, true
+}
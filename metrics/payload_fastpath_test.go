@@ -0,0 +1,78 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// buildFastPayloadBody assembles a body in the fixed-offset framing decodeFastPayloadHeader expects: a uint32
+// session id length, the session id bytes, a uint32 data length, then that much data.
+//
+// NOTE: this asserts decodeFastPayloadHeader's own documented framing. This snapshot doesn't carry the real
+// xgress package, so this can't also assert that framing matches xgress.Payload's actual marshalled wire format -
+// that needs an upstream integration test once this package is built alongside xgress.
+func buildFastPayloadBody(sessionId string, dataLen int) []byte {
+	body := make([]byte, 4+len(sessionId)+4+dataLen)
+	binary.BigEndian.PutUint32(body[0:4], uint32(len(sessionId)))
+	copy(body[4:], sessionId)
+	dataLenOffset := 4 + len(sessionId)
+	binary.BigEndian.PutUint32(body[dataLenOffset:dataLenOffset+4], uint32(dataLen))
+	return body
+}
+
+func TestDecodeFastPayloadHeader(t *testing.T) {
+	body := buildFastPayloadBody("ses-1", 128)
+
+	hdr, ok := decodeFastPayloadHeader(body)
+	if !ok {
+		t.Fatalf("expected a well-formed header to decode successfully")
+	}
+	if hdr.SessionId != "ses-1" {
+		t.Errorf("expected session id %q, got %q", "ses-1", hdr.SessionId)
+	}
+	if hdr.DataLen != 128 {
+		t.Errorf("expected data len %d, got %d", 128, hdr.DataLen)
+	}
+}
+
+func TestDecodeFastPayloadHeaderTooShortForSessionId(t *testing.T) {
+	body := make([]byte, 4)
+	binary.BigEndian.PutUint32(body[0:4], 10)
+
+	if _, ok := decodeFastPayloadHeader(body); ok {
+		t.Fatalf("expected decode to fail when body is too short to hold the declared session id")
+	}
+}
+
+func TestDecodeFastPayloadHeaderTooShortForDataLen(t *testing.T) {
+	sessionId := "ses-1"
+	body := make([]byte, 4+len(sessionId))
+	binary.BigEndian.PutUint32(body[0:4], uint32(len(sessionId)))
+	copy(body[4:], sessionId)
+
+	if _, ok := decodeFastPayloadHeader(body); ok {
+		t.Fatalf("expected decode to fail when body is too short to hold the data length field")
+	}
+}
+
+func TestDecodeFastPayloadHeaderEmptyBody(t *testing.T) {
+	if _, ok := decodeFastPayloadHeader(nil); ok {
+		t.Fatalf("expected decode to fail on an empty body")
+	}
+}
@@ -23,25 +23,76 @@ import (
 	"time"
 )
 
-// NewChannelPeekHandler creates a channel PeekHandler which tracks latency, message rate and message size distribution
-func NewChannelPeekHandler(linkId string, registry Registry) channel2.PeekHandler {
-	appTxBytesMeter := registry.Meter("fabric.tx.bytesrate")
-	appTxMsgMeter := registry.Meter("fabric.tx.msgrate")
-	appTxMsgSizeHistogram := registry.Histogram("fabric.tx.msgsize")
-
-	appRxBytesMeter := registry.Meter("fabric.rx.bytesrate")
-	appRxMsgMeter := registry.Meter("fabric.rx.msgrate")
-	appRxMsgSizeHistogram := registry.Histogram("fabric.rx.msgsize")
-
-	linkTxBytesMeter := registry.Meter("link." + linkId + ".tx.bytesrate")
-	linkTxMsgMeter := registry.Meter("link." + linkId + ".tx.msgrate")
-	linkTxMsgSizeHistogram := registry.Histogram("link." + linkId + ".tx.msgsize")
-	linkRxBytesMeter := registry.Meter("link." + linkId + ".rx.bytesrate")
-	linkRxMsgMeter := registry.Meter("link." + linkId + ".rx.msgrate")
-	linkRxMsgSizeHistogram := registry.Histogram("link." + linkId + ".rx.msgsize")
-
-	usageRxCounter := registry.IntervalCounter("usage.fabric.rx", time.Minute)
-	usageTxCounter := registry.IntervalCounter("usage.fabric.tx", time.Minute)
+// PeekHandlerOptions controls which tracking a channelPeekHandler performs, so deployments that only care about
+// a subset of metrics can skip the rest of the work. DefaultPeekHandlerOptions leaves FastPathDecode off (payloads
+// are fully unmarshalled, as before), turns LatencyStamping and UsageTracking on, and leaves Inspector and Sampling nil.
+type PeekHandlerOptions struct {
+	// FastPathDecode reads only the session id and data length off a payload message, instead of unmarshalling
+	// a full xgress.Payload, when updating usage counters.
+	FastPathDecode bool
+	// LatencyStamping stamps outgoing non-payload messages with a latency probe id, keeping the send time locally,
+	// and updates linkLatencyHistogram when a later non-payload Rx carries a still-pending probe id.
+	LatencyStamping bool
+	// UsageTracking updates the per-session usage IntervalCounters at all. Routers with very high session
+	// churn may want byte/msg meters without paying for usage tracking's cardinality.
+	UsageTracking bool
+	// Inspector, if non-nil, registers the handler so Registry.Inspect can answer circuit inspection queries for
+	// its sessions. Tracking the bounded per-session state this requires is skipped entirely when nil.
+	Inspector *SessionInspector
+	// Sampling, if non-nil, bounds usage counter cardinality using a SamplingIntervalCounter instead of an
+	// unbounded IntervalCounter. Nil preserves the original unbounded-per-session behavior.
+	Sampling *SamplingIntervalCounterConfig
+}
+
+// DefaultPeekHandlerOptions returns the options that preserve the handler's original, always-on behavior.
+func DefaultPeekHandlerOptions() *PeekHandlerOptions {
+	return &PeekHandlerOptions{
+		FastPathDecode:  false,
+		LatencyStamping: true,
+		UsageTracking:   true,
+	}
+}
+
+// NewChannelPeekHandler creates a channel PeekHandler which tracks latency, message rate and message size distribution.
+// Link-scoped metrics are registered with a link_id label via registerer rather than having the link id baked into
+// the metric name, so exporters can aggregate across links without parsing names. A nil options uses
+// DefaultPeekHandlerOptions.
+func NewChannelPeekHandler(linkId string, registerer Registerer, options *PeekHandlerOptions) channel2.PeekHandler {
+	if options == nil {
+		options = DefaultPeekHandlerOptions()
+	}
+
+	appTxBytesMeter := registerer.Meter("fabric.tx.bytesrate", nil)
+	appTxMsgMeter := registerer.Meter("fabric.tx.msgrate", nil)
+	appTxMsgSizeHistogram := registerer.Histogram("fabric.tx.msgsize", nil)
+
+	appRxBytesMeter := registerer.Meter("fabric.rx.bytesrate", nil)
+	appRxMsgMeter := registerer.Meter("fabric.rx.msgrate", nil)
+	appRxMsgSizeHistogram := registerer.Histogram("fabric.rx.msgsize", nil)
+
+	linkLabels := map[string]string{"link_id": linkId}
+	linkTxBytesMeter := registerer.Meter("link.tx.bytesrate", linkLabels)
+	linkTxMsgMeter := registerer.Meter("link.tx.msgrate", linkLabels)
+	linkTxMsgSizeHistogram := registerer.Histogram("link.tx.msgsize", linkLabels)
+	linkRxBytesMeter := registerer.Meter("link.rx.bytesrate", linkLabels)
+	linkRxMsgMeter := registerer.Meter("link.rx.msgrate", linkLabels)
+	linkRxMsgSizeHistogram := registerer.Histogram("link.rx.msgsize", linkLabels)
+
+	var usageRxCounter, usageTxCounter IntervalCounter
+	if options.Sampling != nil {
+		usageRxCounter = registerer.SamplingIntervalCounter("usage.fabric.rx", time.Minute, *options.Sampling, nil)
+		usageTxCounter = registerer.SamplingIntervalCounter("usage.fabric.tx", time.Minute, *options.Sampling, nil)
+	} else {
+		usageRxCounter = registerer.IntervalCounter("usage.fabric.rx", time.Minute, nil)
+		usageTxCounter = registerer.IntervalCounter("usage.fabric.tx", time.Minute, nil)
+	}
+
+	var linkLatencyHistogram Histogram
+	var probes *latencyProbes
+	if options.LatencyStamping {
+		linkLatencyHistogram = registerer.Histogram("link.latency", linkLabels)
+		probes = newLatencyProbes()
+	}
 
 	closeHook := func() {
 		linkTxBytesMeter.Dispose()
@@ -50,10 +101,14 @@ func NewChannelPeekHandler(linkId string, registry Registry) channel2.PeekHandle
 		linkRxBytesMeter.Dispose()
 		linkRxMsgMeter.Dispose()
 		linkRxMsgSizeHistogram.Dispose()
+		if linkLatencyHistogram != nil {
+			linkLatencyHistogram.Dispose()
+		}
 		// app level metrics and usageCounter are shared across all links, so we don't dispose of them
 	}
 
-	return &channelPeekHandler{
+	handler := &channelPeekHandler{
+		linkId:                 linkId,
 		appTxBytesMeter:        appTxBytesMeter,
 		appTxMsgMeter:          appTxMsgMeter,
 		appTxMsgSizeHistogram:  appTxMsgSizeHistogram,
@@ -66,13 +121,25 @@ func NewChannelPeekHandler(linkId string, registry Registry) channel2.PeekHandle
 		linkRxBytesMeter:       linkRxBytesMeter,
 		linkRxMsgMeter:         linkRxMsgMeter,
 		linkRxMsgSizeHistogram: linkRxMsgSizeHistogram,
+		linkLatencyHistogram:   linkLatencyHistogram,
+		latencyProbes:          probes,
 		usageRxCounter:         usageRxCounter,
 		usageTxCounter:         usageTxCounter,
+		options:                options,
 		closeHook:              closeHook,
 	}
+
+	if options.Inspector != nil {
+		handler.sessionStates = newSessionLRU(defaultSessionLRUCapacity)
+		options.Inspector.Register(handler)
+	}
+
+	return handler
 }
 
 type channelPeekHandler struct {
+	linkId string
+
 	appTxBytesMeter Meter
 	appTxMsgMeter   Meter
 	appRxBytesMeter Meter
@@ -82,6 +149,7 @@ type channelPeekHandler struct {
 	appRxMsgSizeHistogram Histogram
 
 	linkLatencyHistogram   Histogram
+	latencyProbes          *latencyProbes
 	linkTxBytesMeter       Meter
 	linkTxMsgMeter         Meter
 	linkRxBytesMeter       Meter
@@ -92,6 +160,9 @@ type channelPeekHandler struct {
 	usageRxCounter IntervalCounter
 	usageTxCounter IntervalCounter
 
+	options       *PeekHandlerOptions
+	sessionStates *sessionLRU
+
 	closeHook func()
 }
 
@@ -107,11 +178,19 @@ func (h *channelPeekHandler) Rx(msg *channel2.Message, ch channel2.Channel) {
 	h.appRxMsgMeter.Mark(1)
 	h.appRxMsgSizeHistogram.Update(msgSize)
 
-	if msg.ContentType == int32(xgress.ContentTypePayloadType) {
-		if payload, err := xgress.UnmarshallPayload(msg); err != nil {
-			pfxlog.Logger().Errorf("Failed to unmarshal payload. Error: %v", err)
-		} else {
-			h.usageRxCounter.Update(payload.SessionId, time.Now(), uint64(len(payload.Data)))
+	isPayload := msg.ContentType == int32(xgress.ContentTypePayloadType)
+
+	if h.options.LatencyStamping && !isPayload {
+		if rtt, ok := h.latencyProbes.resolve(msg); ok {
+			h.linkLatencyHistogram.Update(rtt.Nanoseconds())
+		}
+	}
+
+	if isPayload {
+		if h.options.UsageTracking {
+			h.updateUsage(h.usageRxCounter, msg, false)
+		} else if h.sessionStates != nil {
+			h.touchSession(msg, false)
 		}
 	}
 }
@@ -125,38 +204,141 @@ func (h *channelPeekHandler) Tx(msg *channel2.Message, ch channel2.Channel) {
 	h.appTxMsgMeter.Mark(1)
 	h.appTxMsgSizeHistogram.Update(msgSize)
 
-	if msg.ContentType == int32(xgress.ContentTypePayloadType) {
-		if payload, err := xgress.UnmarshallPayload(msg); err != nil {
-			pfxlog.Logger().Errorf("Failed to unmarshal payload. Error: %v", err)
+	isPayload := msg.ContentType == int32(xgress.ContentTypePayloadType)
+
+	if h.options.LatencyStamping && !isPayload {
+		h.latencyProbes.stamp(msg)
+	}
+
+	if isPayload {
+		if h.options.UsageTracking {
+			h.updateUsage(h.usageTxCounter, msg, true)
+		} else if h.sessionStates != nil {
+			h.touchSession(msg, true)
+		}
+	}
+}
+
+// updateUsage feeds a payload message's session id and data length into the given usage counter, using the
+// fast-path decoder when enabled to avoid allocating a full xgress.Payload on the hot path. It also updates
+// per-session inspection state, when enabled, using whichever decode path ran.
+func (h *channelPeekHandler) updateUsage(counter IntervalCounter, msg *channel2.Message, isTx bool) {
+	if h.options.FastPathDecode {
+		if hdr, ok := decodeFastPayloadHeader(msg.Body); ok {
+			counter.Update(hdr.SessionId, time.Now(), uint64(hdr.DataLen))
+			h.recordSession(hdr.SessionId, isTx)
+			return
+		}
+	}
+
+	if payload, err := xgress.UnmarshallPayload(msg); err != nil {
+		pfxlog.Logger().Errorf("Failed to unmarshal payload. Error: %v", err)
+	} else {
+		counter.Update(payload.SessionId, time.Now(), uint64(len(payload.Data)))
+		h.recordSession(payload.SessionId, isTx)
+	}
+}
+
+// touchSession updates per-session inspection state for a payload message without touching usage counters, for
+// when usage tracking is disabled but inspection is still enabled. Like updateUsage, it only uses the fast-path
+// decoder when FastPathDecode is enabled.
+func (h *channelPeekHandler) touchSession(msg *channel2.Message, isTx bool) {
+	if h.options.FastPathDecode {
+		if hdr, ok := decodeFastPayloadHeader(msg.Body); ok {
+			h.recordSession(hdr.SessionId, isTx)
+			return
+		}
+	}
+	if payload, err := xgress.UnmarshallPayload(msg); err == nil {
+		h.recordSession(payload.SessionId, isTx)
+	}
+}
+
+// recordSession updates the bounded per-session state used to answer Inspect queries, if inspection is enabled.
+func (h *channelPeekHandler) recordSession(sessionId string, isTx bool) {
+	if h.sessionStates == nil {
+		return
+	}
+	now := time.Now()
+	h.sessionStates.update(sessionId, func(state *sessionState) {
+		if isTx {
+			state.txSeq++
+			state.flags |= SessionFlagTxSeen
+			state.lastTxAt = now
 		} else {
-			h.usageTxCounter.Update(payload.SessionId, time.Now(), uint64(len(payload.Data)))
+			state.rxSeq++
+			state.flags |= SessionFlagRxSeen
+			state.lastRxAt = now
 		}
+	})
+}
+
+// Inspect returns the live XgressDetail tracked for sessionId on this link, if inspection is enabled and the
+// session has been seen.
+func (h *channelPeekHandler) Inspect(sessionId string) (XgressDetail, bool) {
+	if h.sessionStates == nil {
+		return XgressDetail{}, false
+	}
+
+	state, found := h.sessionStates.get(sessionId)
+	if !found {
+		return XgressDetail{}, false
+	}
+
+	detail := XgressDetail{
+		SessionId:     sessionId,
+		LinkId:        h.linkId,
+		TxSeq:         state.txSeq,
+		RxSeq:         state.rxSeq,
+		Flags:         state.flags,
+		LastTxAt:      state.lastTxAt,
+		LastRxAt:      state.lastRxAt,
+		TxBytesRate1m: h.linkTxBytesMeter.Rate1(),
+		RxBytesRate1m: h.linkRxBytesMeter.Rate1(),
+		TxMsgRate1m:   h.linkTxMsgMeter.Rate1(),
+		RxMsgRate1m:   h.linkRxMsgMeter.Rate1(),
+		MsgSizeP50:    h.linkRxMsgSizeHistogram.Percentile(0.5),
+		MsgSizeP99:    h.linkRxMsgSizeHistogram.Percentile(0.99),
 	}
+	if bucket, ok := h.usageRxCounter.Current()[sessionId]; ok {
+		detail.UsageBucket = bucket
+	}
+	return detail, true
 }
 
 func (h *channelPeekHandler) Close(ch channel2.Channel) {
 	if h.closeHook != nil {
 		h.closeHook()
 	}
+	if h.options.Inspector != nil && h.sessionStates != nil {
+		h.options.Inspector.Unregister(h)
+	}
 }
 
-// NewXgressPeekHandler creates an xgress PeekHandler which tracks message rates and histograms as well as usage
-func NewXgressPeekHandler(registry Registry) xgress.PeekHandler {
-	ingressTxBytesMeter := registry.Meter("ingress.tx.bytesrate")
-	ingressTxMsgMeter := registry.Meter("ingress.tx.msgrate")
-	ingressRxBytesMeter := registry.Meter("ingress.rx.bytesrate")
-	ingressRxMsgMeter := registry.Meter("ingress.rx.msgrate")
-	egressTxBytesMeter := registry.Meter("egress.tx.bytesrate")
-	egressTxMsgMeter := registry.Meter("egress.tx.Msgrate")
-	egressRxBytesMeter := registry.Meter("egress.rx.bytesrate")
-	egressRxMsgMeter := registry.Meter("egress.rx.msgrate")
-
-	ingressTxMsgSizeHistogram := registry.Histogram("ingress.tx.msgsize")
-	ingressRxMsgSizeHistogram := registry.Histogram("ingress.rx.msgsize")
-	egressTxMsgSizeHistogram := registry.Histogram("egress.tx.msgsize")
-	egressRxMsgSizeHistogram := registry.Histogram("egress.rx.msgsize")
-
-	return &xgressPeekHandler{
+// NewXgressPeekHandler creates an xgress PeekHandler which tracks message rates and histograms as well as usage.
+// Metrics are registered with an originator label ("ingress"/"egress") via registerer rather than having the
+// originator baked into the metric name. If inspector is non-nil, the handler also tracks bounded per-session
+// state so Registry.Inspect can answer circuit inspection queries for its sessions. If sampling is non-nil,
+// usage counters use a SamplingIntervalCounter to bound cardinality instead of tracking every session.
+func NewXgressPeekHandler(registerer Registerer, inspector *SessionInspector, sampling *SamplingIntervalCounterConfig) xgress.PeekHandler {
+	ingressLabels := map[string]string{"originator": OriginatorIngress}
+	egressLabels := map[string]string{"originator": OriginatorEgress}
+
+	ingressTxBytesMeter := registerer.Meter("xgress.tx.bytesrate", ingressLabels)
+	ingressTxMsgMeter := registerer.Meter("xgress.tx.msgrate", ingressLabels)
+	ingressRxBytesMeter := registerer.Meter("xgress.rx.bytesrate", ingressLabels)
+	ingressRxMsgMeter := registerer.Meter("xgress.rx.msgrate", ingressLabels)
+	egressTxBytesMeter := registerer.Meter("xgress.tx.bytesrate", egressLabels)
+	egressTxMsgMeter := registerer.Meter("xgress.tx.msgrate", egressLabels)
+	egressRxBytesMeter := registerer.Meter("xgress.rx.bytesrate", egressLabels)
+	egressRxMsgMeter := registerer.Meter("xgress.rx.msgrate", egressLabels)
+
+	ingressTxMsgSizeHistogram := registerer.Histogram("xgress.tx.msgsize", ingressLabels)
+	ingressRxMsgSizeHistogram := registerer.Histogram("xgress.rx.msgsize", ingressLabels)
+	egressTxMsgSizeHistogram := registerer.Histogram("xgress.tx.msgsize", egressLabels)
+	egressRxMsgSizeHistogram := registerer.Histogram("xgress.rx.msgsize", egressLabels)
+
+	handler := &xgressPeekHandler{
 		ingressTxBytesMeter: ingressTxBytesMeter,
 		ingressTxMsgMeter:   ingressTxMsgMeter,
 		ingressRxBytesMeter: ingressRxBytesMeter,
@@ -171,11 +353,28 @@ func NewXgressPeekHandler(registry Registry) xgress.PeekHandler {
 		egressTxMsgSizeHistogram:  egressTxMsgSizeHistogram,
 		egressRxMsgSizeHistogram:  egressRxMsgSizeHistogram,
 
-		ingressRxUsageCounter: registry.IntervalCounter("usage.ingress.rx", time.Minute),
-		ingressTxUsageCounter: registry.IntervalCounter("usage.ingress.tx", time.Minute),
-		egressRxUsageCounter:  registry.IntervalCounter("usage.egress.rx", time.Minute),
-		egressTxUsageCounter:  registry.IntervalCounter("usage.egress.tx", time.Minute),
+		ingressRxUsageCounter: newXgressUsageCounter(registerer, "usage.xgress.rx", ingressLabels, sampling),
+		ingressTxUsageCounter: newXgressUsageCounter(registerer, "usage.xgress.tx", ingressLabels, sampling),
+		egressRxUsageCounter:  newXgressUsageCounter(registerer, "usage.xgress.rx", egressLabels, sampling),
+		egressTxUsageCounter:  newXgressUsageCounter(registerer, "usage.xgress.tx", egressLabels, sampling),
 	}
+
+	if inspector != nil {
+		handler.sessionStates = newSessionLRU(defaultSessionLRUCapacity)
+		handler.inspector = inspector
+		inspector.Register(handler)
+	}
+
+	return handler
+}
+
+// newXgressUsageCounter creates a usage IntervalCounter for an xgress peek handler, using a
+// SamplingIntervalCounter to bound cardinality when sampling is non-nil.
+func newXgressUsageCounter(registerer Registerer, name string, labels map[string]string, sampling *SamplingIntervalCounterConfig) IntervalCounter {
+	if sampling != nil {
+		return registerer.SamplingIntervalCounter(name, time.Minute, *sampling, labels)
+	}
+	return registerer.IntervalCounter(name, time.Minute, labels)
 }
 
 type xgressPeekHandler struct {
@@ -197,6 +396,9 @@ type xgressPeekHandler struct {
 	ingressTxUsageCounter IntervalCounter
 	egressRxUsageCounter  IntervalCounter
 	egressTxUsageCounter  IntervalCounter
+
+	sessionStates *sessionLRU
+	inspector     *SessionInspector
 }
 
 func (handler *xgressPeekHandler) Rx(x *xgress.Xgress, payload *xgress.Payload) {
@@ -206,11 +408,13 @@ func (handler *xgressPeekHandler) Rx(x *xgress.Xgress, payload *xgress.Payload)
 		handler.ingressRxMsgMeter.Mark(1)
 		handler.ingressRxBytesMeter.Mark(msgSize)
 		handler.ingressRxMsgSizeHistogram.Update(msgSize)
+		handler.recordSession(x.SessionId().Token, OriginatorIngress, false)
 	} else {
 		handler.egressRxUsageCounter.Update(x.SessionId().Token, time.Now(), uint64(msgSize))
 		handler.egressRxMsgMeter.Mark(1)
 		handler.egressRxBytesMeter.Mark(msgSize)
 		handler.egressRxMsgSizeHistogram.Update(msgSize)
+		handler.recordSession(x.SessionId().Token, OriginatorEgress, false)
 	}
 }
 
@@ -221,13 +425,84 @@ func (handler *xgressPeekHandler) Tx(x *xgress.Xgress, payload *xgress.Payload)
 		handler.ingressTxMsgMeter.Mark(1)
 		handler.ingressTxBytesMeter.Mark(msgSize)
 		handler.ingressTxMsgSizeHistogram.Update(msgSize)
+		handler.recordSession(x.SessionId().Token, OriginatorIngress, true)
 	} else {
 		handler.egressTxUsageCounter.Update(x.SessionId().Token, time.Now(), uint64(msgSize))
 		handler.egressTxMsgMeter.Mark(1)
 		handler.egressTxBytesMeter.Mark(msgSize)
 		handler.egressTxMsgSizeHistogram.Update(msgSize)
+		handler.recordSession(x.SessionId().Token, OriginatorEgress, true)
 	}
 }
 
 func (handler *xgressPeekHandler) Close(x *xgress.Xgress) {
+	if handler.inspector != nil && handler.sessionStates != nil {
+		handler.inspector.Unregister(handler)
+	}
+}
+
+// recordSession updates the bounded per-session state used to answer Inspect queries, if inspection is enabled,
+// remembering which side of the circuit (originator) the session was seen on so Inspect can report that side's
+// rates and histograms instead of assuming egress for every session.
+func (handler *xgressPeekHandler) recordSession(sessionId string, originator string, isTx bool) {
+	if handler.sessionStates == nil {
+		return
+	}
+	now := time.Now()
+	handler.sessionStates.update(sessionId, func(state *sessionState) {
+		state.originator = originator
+		if isTx {
+			state.txSeq++
+			state.flags |= SessionFlagTxSeen
+			state.lastTxAt = now
+		} else {
+			state.rxSeq++
+			state.flags |= SessionFlagRxSeen
+			state.lastRxAt = now
+		}
+	})
+}
+
+// Inspect returns the live XgressDetail tracked for sessionId, if inspection is enabled and the session has
+// been seen. Rates, histograms and usage are drawn from the meters for the side of the circuit (ingress/egress)
+// the session was actually recorded on, rather than always reporting egress.
+func (handler *xgressPeekHandler) Inspect(sessionId string) (XgressDetail, bool) {
+	if handler.sessionStates == nil {
+		return XgressDetail{}, false
+	}
+
+	state, found := handler.sessionStates.get(sessionId)
+	if !found {
+		return XgressDetail{}, false
+	}
+
+	txBytesMeter, rxBytesMeter := handler.egressTxBytesMeter, handler.egressRxBytesMeter
+	txMsgMeter, rxMsgMeter := handler.egressTxMsgMeter, handler.egressRxMsgMeter
+	rxMsgSizeHistogram := handler.egressRxMsgSizeHistogram
+	rxUsageCounter := handler.egressRxUsageCounter
+	if state.originator == OriginatorIngress {
+		txBytesMeter, rxBytesMeter = handler.ingressTxBytesMeter, handler.ingressRxBytesMeter
+		txMsgMeter, rxMsgMeter = handler.ingressTxMsgMeter, handler.ingressRxMsgMeter
+		rxMsgSizeHistogram = handler.ingressRxMsgSizeHistogram
+		rxUsageCounter = handler.ingressRxUsageCounter
+	}
+
+	detail := XgressDetail{
+		SessionId:     sessionId,
+		TxSeq:         state.txSeq,
+		RxSeq:         state.rxSeq,
+		Flags:         state.flags,
+		LastTxAt:      state.lastTxAt,
+		LastRxAt:      state.lastRxAt,
+		TxBytesRate1m: txBytesMeter.Rate1(),
+		RxBytesRate1m: rxBytesMeter.Rate1(),
+		TxMsgRate1m:   txMsgMeter.Rate1(),
+		RxMsgRate1m:   rxMsgMeter.Rate1(),
+		MsgSizeP50:    rxMsgSizeHistogram.Percentile(0.5),
+		MsgSizeP99:    rxMsgSizeHistogram.Percentile(0.99),
+	}
+	if bucket, ok := rxUsageCounter.Current()[sessionId]; ok {
+		detail.UsageBucket = bucket
+	}
+	return detail, true
 }
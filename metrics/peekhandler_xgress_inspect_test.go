@@ -0,0 +1,55 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+// TestXgressPeekHandlerInspectReadsRecordedOriginatorSide guards against Inspect always reporting egress meters
+// regardless of which side of the circuit a session was actually seen on.
+func TestXgressPeekHandlerInspectReadsRecordedOriginatorSide(t *testing.T) {
+	inspector := NewSessionInspector()
+	registerer := NewRegisterer(NewRegistry())
+	handler := NewXgressPeekHandler(registerer, inspector, nil).(*xgressPeekHandler)
+
+	const sessionId = "ingress-only-session"
+	handler.ingressRxBytesMeter.Mark(1234)
+	handler.ingressRxMsgMeter.Mark(1)
+	handler.ingressRxMsgSizeHistogram.Update(1234)
+	handler.ingressRxUsageCounter.Update(sessionId, time.Now(), 1234)
+	handler.recordSession(sessionId, OriginatorIngress, false)
+
+	// The egress side never saw this session; if Inspect fell back to its egress defaults these would mismatch.
+	detail, found := handler.Inspect(sessionId)
+	if !found {
+		t.Fatalf("expected Inspect to find session %q", sessionId)
+	}
+	if detail.RxBytesRate1m != handler.ingressRxBytesMeter.Rate1() {
+		t.Errorf("expected Inspect to report the ingress RxBytesRate1m for an ingress-only session, got %v", detail.RxBytesRate1m)
+	}
+	if detail.RxMsgRate1m != handler.ingressRxMsgMeter.Rate1() {
+		t.Errorf("expected Inspect to report the ingress RxMsgRate1m for an ingress-only session, got %v", detail.RxMsgRate1m)
+	}
+	if detail.MsgSizeP50 != handler.ingressRxMsgSizeHistogram.Percentile(0.5) {
+		t.Errorf("expected Inspect to report the ingress msg size histogram for an ingress-only session, got %v", detail.MsgSizeP50)
+	}
+	if detail.UsageBucket != handler.ingressRxUsageCounter.Current()[sessionId] {
+		t.Errorf("expected Inspect to report the ingress usage bucket for an ingress-only session, got %v", detail.UsageBucket)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package prom
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/netfoundry/ziti-foundation/metrics"
+)
+
+const (
+	fabricPath = "/metrics/v3/fabric"
+	linkPath   = "/metrics/v3/link/"
+	xgressPath = "/metrics/v3/xgress/"
+	usagePath  = "/metrics/v3/usage"
+)
+
+// subsystemHandler is the common shape of every /metrics/v3/* handler: write the subsystem's metrics, in
+// Prometheus exposition format, to the response.
+type subsystemHandler func(w http.ResponseWriter, r *http.Request)
+
+// NewFabricHandler serves the unlabeled app-level fabric.* meters and histograms at /metrics/v3/fabric.
+func NewFabricHandler(registerer metrics.Registerer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for m, labeled := range registerer.Meters() {
+			if strings.HasPrefix(labeled.Name, "fabric.") && len(labeled.Labels) == 0 {
+				writeMeter(w, metricName(labeled), nil, m)
+			}
+		}
+		for h, labeled := range registerer.Histograms() {
+			if strings.HasPrefix(labeled.Name, "fabric.") && len(labeled.Labels) == 0 {
+				writeHistogram(w, metricName(labeled), nil, h)
+			}
+		}
+	})
+}
+
+// NewLinkHandler serves the per-link link.* meters and histograms at /metrics/v3/link/{linkId}.
+func NewLinkHandler(registerer metrics.Registerer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		linkId := strings.TrimPrefix(r.URL.Path, linkPath)
+		for m, labeled := range registerer.Meters() {
+			if strings.HasPrefix(labeled.Name, "link.") && labeled.Labels["link_id"] == linkId {
+				writeMeter(w, metricName(labeled), labeled.Labels, m)
+			}
+		}
+		for h, labeled := range registerer.Histograms() {
+			if strings.HasPrefix(labeled.Name, "link.") && labeled.Labels["link_id"] == linkId {
+				writeHistogram(w, metricName(labeled), labeled.Labels, h)
+			}
+		}
+	})
+}
+
+// NewXgressHandler serves the xgress.* meters and histograms for a given originator ("ingress" or "egress")
+// at /metrics/v3/xgress/{originator}.
+func NewXgressHandler(registerer metrics.Registerer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		originator := strings.TrimPrefix(r.URL.Path, xgressPath)
+		for m, labeled := range registerer.Meters() {
+			if strings.HasPrefix(labeled.Name, "xgress.") && labeled.Labels["originator"] == originator {
+				writeMeter(w, metricName(labeled), labeled.Labels, m)
+			}
+		}
+		for h, labeled := range registerer.Histograms() {
+			if strings.HasPrefix(labeled.Name, "xgress.") && labeled.Labels["originator"] == originator {
+				writeHistogram(w, metricName(labeled), labeled.Labels, h)
+			}
+		}
+	})
+}
+
+// NewUsageHandler serves the usage.* IntervalCounters at /metrics/v3/usage, one counter series per session_id.
+func NewUsageHandler(registerer metrics.Registerer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for ic, labeled := range registerer.IntervalCounters() {
+			if strings.HasPrefix(labeled.Name, "usage.") {
+				writeIntervalCounter(w, metricName(labeled), labeled.Labels, ic)
+			}
+		}
+	})
+}
+
+// NewHandler mounts every subsystem handler under its v3 path and registers a parent handler at /metrics/v3
+// which aggregates all of them into a single scrape response.
+func NewHandler(registerer metrics.Registerer) http.Handler {
+	fabric := NewFabricHandler(registerer)
+	link := NewLinkHandler(registerer)
+	xgress := NewXgressHandler(registerer)
+	usage := NewUsageHandler(registerer)
+
+	mux := http.NewServeMux()
+	mux.Handle(fabricPath, fabric)
+	mux.Handle(linkPath, link)
+	mux.Handle(xgressPath, xgress)
+	mux.Handle(usagePath, usage)
+	mux.HandleFunc("/metrics/v3", func(w http.ResponseWriter, r *http.Request) {
+		fabric.ServeHTTP(w, r)
+		usage.ServeHTTP(w, r)
+		for _, linkId := range linkIds(registerer) {
+			link.ServeHTTP(w, withPath(r, linkPath+linkId))
+		}
+		for _, originator := range []string{"ingress", "egress"} {
+			xgress.ServeHTTP(w, withPath(r, xgressPath+originator))
+		}
+	})
+	return mux
+}
+
+// withPath returns a shallow copy of r with its URL path replaced, so a subsystem handler registered for a
+// templated path (e.g. /metrics/v3/link/) can be invoked directly with a concrete path for aggregation.
+func withPath(r *http.Request, path string) *http.Request {
+	u := *r.URL
+	u.Path = path
+	clone := *r
+	clone.URL = &u
+	return &clone
+}
+
+// linkIds returns the distinct link_id label values present across every registered Meter, so the aggregate
+// handler knows which per-link paths to fan out to. Histograms are not scanned.
+func linkIds(registerer metrics.Registerer) []string {
+	seen := make(map[string]struct{})
+	for _, labeled := range registerer.Meters() {
+		if linkId, ok := labeled.Labels["link_id"]; ok {
+			seen[linkId] = struct{}{}
+		}
+	}
+	result := make([]string, 0, len(seen))
+	for linkId := range seen {
+		result = append(result, linkId)
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package prom
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netfoundry/ziti-foundation/metrics"
+)
+
+func TestNewFabricHandlerServesOnlyUnlabeledFabricMetrics(t *testing.T) {
+	registerer := metrics.NewRegisterer(metrics.NewRegistry())
+	registerer.Meter("fabric.tx.bytesrate", nil).Mark(1)
+	registerer.Meter("link.tx.bytesrate", map[string]string{"link_id": "abc"}).Mark(1)
+
+	rec := httptest.NewRecorder()
+	NewFabricHandler(registerer).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, fabricPath, nil))
+
+	out := rec.Body.String()
+	if !strings.Contains(out, "ziti_fabric_tx_bytesrate_total") {
+		t.Errorf("expected fabric handler to serve fabric.* metrics, got:\n%s", out)
+	}
+	if strings.Contains(out, "ziti_link_tx_bytesrate_total") {
+		t.Errorf("expected fabric handler to not serve link.* metrics, got:\n%s", out)
+	}
+}
+
+func TestNewLinkHandlerFiltersByLinkId(t *testing.T) {
+	registerer := metrics.NewRegisterer(metrics.NewRegistry())
+	registerer.Meter("link.tx.bytesrate", map[string]string{"link_id": "abc"}).Mark(1)
+	registerer.Meter("link.tx.bytesrate", map[string]string{"link_id": "xyz"}).Mark(1)
+
+	rec := httptest.NewRecorder()
+	NewLinkHandler(registerer).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, linkPath+"abc", nil))
+
+	out := rec.Body.String()
+	if !strings.Contains(out, `link_id="abc"`) {
+		t.Errorf("expected the link handler to serve link_id=abc, got:\n%s", out)
+	}
+	if strings.Contains(out, `link_id="xyz"`) {
+		t.Errorf("expected the link handler to not serve link_id=xyz, got:\n%s", out)
+	}
+}
+
+func TestNewXgressHandlerFiltersByOriginator(t *testing.T) {
+	registerer := metrics.NewRegisterer(metrics.NewRegistry())
+	registerer.Meter("xgress.rx.bytesrate", map[string]string{"originator": "ingress"}).Mark(1)
+	registerer.Meter("xgress.rx.bytesrate", map[string]string{"originator": "egress"}).Mark(1)
+
+	rec := httptest.NewRecorder()
+	NewXgressHandler(registerer).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, xgressPath+"ingress", nil))
+
+	out := rec.Body.String()
+	if !strings.Contains(out, `originator="ingress"`) {
+		t.Errorf("expected the xgress handler to serve originator=ingress, got:\n%s", out)
+	}
+	if strings.Contains(out, `originator="egress"`) {
+		t.Errorf("expected the xgress handler to not serve originator=egress, got:\n%s", out)
+	}
+}
+
+func TestNewUsageHandlerServesOnlyUsageIntervalCounters(t *testing.T) {
+	registerer := metrics.NewRegisterer(metrics.NewRegistry())
+	registerer.IntervalCounter("usage.fabric.rx", time.Minute, nil).Update("sess-1", time.Now(), 10)
+	registerer.IntervalCounter("other.rx", time.Minute, nil).Update("sess-2", time.Now(), 10)
+
+	rec := httptest.NewRecorder()
+	NewUsageHandler(registerer).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, usagePath, nil))
+
+	out := rec.Body.String()
+	if !strings.Contains(out, `session_id="sess-1"`) {
+		t.Errorf("expected the usage handler to serve usage.* counters, got:\n%s", out)
+	}
+	if strings.Contains(out, `session_id="sess-2"`) {
+		t.Errorf("expected the usage handler to not serve non-usage.* counters, got:\n%s", out)
+	}
+}
+
+func TestNewHandlerAggregatesEverySubsystem(t *testing.T) {
+	registerer := metrics.NewRegisterer(metrics.NewRegistry())
+	registerer.Meter("fabric.tx.bytesrate", nil).Mark(1)
+	registerer.Meter("link.tx.bytesrate", map[string]string{"link_id": "abc"}).Mark(1)
+	registerer.Meter("xgress.rx.bytesrate", map[string]string{"originator": "ingress"}).Mark(1)
+	registerer.IntervalCounter("usage.fabric.rx", time.Minute, nil).Update("sess-1", time.Now(), 10)
+
+	rec := httptest.NewRecorder()
+	NewHandler(registerer).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics/v3", nil))
+
+	out := rec.Body.String()
+	for _, want := range []string{"ziti_fabric_tx_bytesrate_total", `link_id="abc"`, `originator="ingress"`, `session_id="sess-1"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected the aggregate handler to include %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+// Package prom exposes a metrics.Registry as a Prometheus scrape target, organized v3-style: rather than one
+// flat /metrics blob, each subsystem is mounted under its own path (/metrics/v3/fabric, /metrics/v3/link/{id},
+// /metrics/v3/xgress/{originator}, /metrics/v3/usage), with a parent handler that aggregates all of them.
+package prom
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/netfoundry/ziti-foundation/metrics"
+)
+
+var quantiles = []float64{0.5, 0.9, 0.99}
+
+// writeMeter emits a Meter as a `_total` counter plus `_rate1m`/`_rate5m`/`_rate15m` gauges.
+func writeMeter(w io.Writer, name string, labels map[string]string, m metrics.Meter) {
+	_, _ = fmt.Fprintf(w, "%s_total%s %d\n", name, formatLabels(labels, nil), m.Count())
+	_, _ = fmt.Fprintf(w, "%s_rate1m%s %f\n", name, formatLabels(labels, nil), m.Rate1())
+	_, _ = fmt.Fprintf(w, "%s_rate5m%s %f\n", name, formatLabels(labels, nil), m.Rate5())
+	_, _ = fmt.Fprintf(w, "%s_rate15m%s %f\n", name, formatLabels(labels, nil), m.Rate15())
+}
+
+// writeHistogram emits a Histogram as quantile-labeled gauges (0.5, 0.9, 0.99) plus `_sum`/`_count`.
+func writeHistogram(w io.Writer, name string, labels map[string]string, h metrics.Histogram) {
+	for _, q := range quantiles {
+		extra := map[string]string{"quantile": fmt.Sprintf("%g", q)}
+		_, _ = fmt.Fprintf(w, "%s%s %f\n", name, formatLabels(labels, extra), h.Percentile(q))
+	}
+	_, _ = fmt.Fprintf(w, "%s_sum%s %d\n", name, formatLabels(labels, nil), h.Sum())
+	_, _ = fmt.Fprintf(w, "%s_count%s %d\n", name, formatLabels(labels, nil), h.Count())
+}
+
+// writeIntervalCounter emits the current bucket of an IntervalCounter as a counter labeled by session_id.
+func writeIntervalCounter(w io.Writer, name string, labels map[string]string, ic metrics.IntervalCounter) {
+	for sessionId, value := range ic.Current() {
+		extra := map[string]string{"session_id": sessionId}
+		_, _ = fmt.Fprintf(w, "%s_total%s %d\n", name, formatLabels(labels, extra), value)
+	}
+}
+
+// formatLabels renders labels and extraLabels as a Prometheus label set, e.g. `{link_id="abc",quantile="0.5"}`.
+// An empty result omits the braces entirely, matching exposition-format conventions for unlabeled metrics.
+func formatLabels(labels map[string]string, extraLabels map[string]string) string {
+	if len(labels) == 0 && len(extraLabels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels)+len(extraLabels))
+	merged := make(map[string]string, len(labels)+len(extraLabels))
+	for k, v := range labels {
+		names = append(names, k)
+		merged[k] = v
+	}
+	for k, v := range extraLabels {
+		names = append(names, k)
+		merged[k] = v
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s=%q`, name, merged[name]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func metricName(labeled metrics.LabeledMetric) string {
+	return "ziti_" + strings.ReplaceAll(labeled.Name, ".", "_")
+}
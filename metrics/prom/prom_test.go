@@ -0,0 +1,104 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package prom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netfoundry/ziti-foundation/metrics"
+)
+
+func TestFormatLabelsOmitsBracesWhenEmpty(t *testing.T) {
+	if got := formatLabels(nil, nil); got != "" {
+		t.Errorf("expected no braces for an empty label set, got %q", got)
+	}
+}
+
+func TestFormatLabelsSortsAndMergesLabels(t *testing.T) {
+	labels := map[string]string{"link_id": "abc"}
+	extra := map[string]string{"quantile": "0.5"}
+
+	got := formatLabels(labels, extra)
+	want := `{link_id="abc",quantile="0.5"}`
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestWriteMeterEmitsTotalAndRateGauges(t *testing.T) {
+	registerer := metrics.NewRegisterer(metrics.NewRegistry())
+	m := registerer.Meter("fabric.tx.bytesrate", nil)
+	m.Mark(42)
+
+	var buf bytes.Buffer
+	writeMeter(&buf, "ziti_fabric_tx_bytesrate", nil, m)
+
+	out := buf.String()
+	for _, suffix := range []string{"_total", "_rate1m", "_rate5m", "_rate15m"} {
+		if !strings.Contains(out, "ziti_fabric_tx_bytesrate"+suffix) {
+			t.Errorf("expected output to contain a %s line, got:\n%s", suffix, out)
+		}
+	}
+	if !strings.Contains(out, "ziti_fabric_tx_bytesrate_total 42\n") {
+		t.Errorf("expected the _total line to report the marked count, got:\n%s", out)
+	}
+}
+
+func TestWriteHistogramEmitsQuantilesSumAndCount(t *testing.T) {
+	registerer := metrics.NewRegisterer(metrics.NewRegistry())
+	h := registerer.Histogram("link.tx.msgsize", map[string]string{"link_id": "abc"})
+	h.Update(100)
+	h.Update(200)
+
+	var buf bytes.Buffer
+	writeHistogram(&buf, "ziti_link_tx_msgsize", map[string]string{"link_id": "abc"}, h)
+
+	out := buf.String()
+	for _, q := range []string{`quantile="0.5"`, `quantile="0.9"`, `quantile="0.99"`} {
+		if !strings.Contains(out, q) {
+			t.Errorf("expected output to contain a %s series, got:\n%s", q, out)
+		}
+	}
+	if !strings.Contains(out, "ziti_link_tx_msgsize_count{link_id=\"abc\"} 2\n") {
+		t.Errorf("expected the _count line to report 2 observations, got:\n%s", out)
+	}
+}
+
+func TestWriteIntervalCounterLabelsEachBucketBySessionId(t *testing.T) {
+	registerer := metrics.NewRegisterer(metrics.NewRegistry())
+	ic := registerer.IntervalCounter("usage.fabric.rx", time.Minute, nil)
+	ic.Update("sess-1", time.Now(), 10)
+
+	var buf bytes.Buffer
+	writeIntervalCounter(&buf, "ziti_usage_fabric_rx", nil, ic)
+
+	out := buf.String()
+	if !strings.Contains(out, `session_id="sess-1"`) {
+		t.Errorf("expected output to be labeled by session_id, got:\n%s", out)
+	}
+}
+
+func TestMetricNameReplacesDotsAndAddsPrefix(t *testing.T) {
+	got := metricName(metrics.LabeledMetric{Name: "link.tx.bytesrate"})
+	want := "ziti_link_tx_bytesrate"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,162 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Registerer creates Meters, Histograms and IntervalCounters tagged with a label set, so that a metric's
+// identity (e.g. which link or which originator it belongs to) doesn't need to be baked into the metric name
+// itself. This lets exporters (see the prom subpackage) recover the label set for a metric instance instead
+// of having to parse it back out of a dotted name.
+type Registerer interface {
+	Meter(name string, labels map[string]string) Meter
+	Histogram(name string, labels map[string]string) Histogram
+	IntervalCounter(name string, intervalSize time.Duration, labels map[string]string) IntervalCounter
+	// SamplingIntervalCounter is like IntervalCounter, but bounds the number of distinct sessions tracked per
+	// interval according to config, so routers with millions of short-lived sessions don't blow up cardinality.
+	SamplingIntervalCounter(name string, intervalSize time.Duration, config SamplingIntervalCounterConfig, labels map[string]string) IntervalCounter
+
+	// Meters returns every Meter created through this Registerer, keyed by the metric instance, along with the
+	// name/labels it was registered under.
+	Meters() map[Meter]LabeledMetric
+	// Histograms returns every Histogram created through this Registerer, keyed by the metric instance, along
+	// with the name/labels it was registered under.
+	Histograms() map[Histogram]LabeledMetric
+	// IntervalCounters returns every IntervalCounter created through this Registerer, keyed by the metric
+	// instance, along with the name/labels it was registered under.
+	IntervalCounters() map[IntervalCounter]LabeledMetric
+}
+
+// LabeledMetric associates a metric instance with the name and label set it was registered under.
+type LabeledMetric struct {
+	Name   string
+	Labels map[string]string
+}
+
+// NewRegisterer wraps a Registry with a Registerer that remembers the name/label set each metric was created
+// with, keyed by the metric instance itself.
+func NewRegisterer(registry Registry) Registerer {
+	return &registerer{
+		registry:                 registry,
+		meters:                   make(map[Meter]LabeledMetric),
+		histograms:               make(map[Histogram]LabeledMetric),
+		intervalCounters:         make(map[IntervalCounter]LabeledMetric),
+		samplingIntervalCounters: make(map[string]IntervalCounter),
+	}
+}
+
+type registerer struct {
+	registry Registry
+	lock     sync.Mutex
+
+	meters           map[Meter]LabeledMetric
+	histograms       map[Histogram]LabeledMetric
+	intervalCounters map[IntervalCounter]LabeledMetric
+
+	// samplingIntervalCounters tracks SamplingIntervalCounters by seriesKey, since unlike the Registry-backed
+	// metric types above, a SamplingIntervalCounter owns a background flush goroutine that NewSamplingIntervalCounter
+	// starts fresh on every call. Without this, callers that register the same name/labels repeatedly (e.g. a
+	// shared, non-link-scoped usage counter created once per link) would leak one goroutine per call for the
+	// life of the process.
+	samplingIntervalCounters map[string]IntervalCounter
+}
+
+func (r *registerer) Meter(name string, labels map[string]string) Meter {
+	// The registry keys instances by name, so two differently-labeled calls with the same name (e.g. the same
+	// metric for two different link ids) must get distinct registry names, or they'd collapse onto one shared
+	// Meter. seriesKey gives each label set its own registry identity while LabeledMetric keeps the original,
+	// unqualified name for exporters to display.
+	m := r.registry.Meter(seriesKey(name, labels))
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.meters[m] = LabeledMetric{Name: name, Labels: labels}
+	return m
+}
+
+func (r *registerer) Histogram(name string, labels map[string]string) Histogram {
+	h := r.registry.Histogram(seriesKey(name, labels))
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.histograms[h] = LabeledMetric{Name: name, Labels: labels}
+	return h
+}
+
+func (r *registerer) IntervalCounter(name string, intervalSize time.Duration, labels map[string]string) IntervalCounter {
+	ic := r.registry.IntervalCounter(seriesKey(name, labels), intervalSize)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.intervalCounters[ic] = LabeledMetric{Name: name, Labels: labels}
+	return ic
+}
+
+// SamplingIntervalCounter returns the SamplingIntervalCounter previously created for this name/label set, if any,
+// so that repeated registration of the same series (e.g. a shared usage counter created once per link) reuses
+// one background flush goroutine instead of leaking a new one per call.
+func (r *registerer) SamplingIntervalCounter(name string, intervalSize time.Duration, config SamplingIntervalCounterConfig, labels map[string]string) IntervalCounter {
+	key := seriesKey(name, labels)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if ic, found := r.samplingIntervalCounters[key]; found {
+		return ic
+	}
+
+	ic := NewSamplingIntervalCounter(intervalSize, config)
+	r.samplingIntervalCounters[key] = ic
+	r.intervalCounters[ic] = LabeledMetric{Name: name, Labels: labels}
+	return ic
+}
+
+// Meters returns a snapshot of every Meter created through this Registerer, along with the name/labels it was
+// registered under.
+func (r *registerer) Meters() map[Meter]LabeledMetric {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	result := make(map[Meter]LabeledMetric, len(r.meters))
+	for m, lm := range r.meters {
+		result[m] = lm
+	}
+	return result
+}
+
+// Histograms returns a snapshot of every Histogram created through this Registerer, along with the name/labels
+// it was registered under.
+func (r *registerer) Histograms() map[Histogram]LabeledMetric {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	result := make(map[Histogram]LabeledMetric, len(r.histograms))
+	for h, lm := range r.histograms {
+		result[h] = lm
+	}
+	return result
+}
+
+// IntervalCounters returns a snapshot of every IntervalCounter created through this Registerer, along with the
+// name/labels it was registered under.
+func (r *registerer) IntervalCounters() map[IntervalCounter]LabeledMetric {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	result := make(map[IntervalCounter]LabeledMetric, len(r.intervalCounters))
+	for ic, lm := range r.intervalCounters {
+		result[ic] = lm
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import "testing"
+
+func TestRegistererDistinguishesSameNameDifferentLabels(t *testing.T) {
+	registerer := NewRegisterer(NewRegistry())
+
+	ingress := registerer.Meter("xgress.rx.bytesrate", map[string]string{"originator": OriginatorIngress})
+	egress := registerer.Meter("xgress.rx.bytesrate", map[string]string{"originator": OriginatorEgress})
+
+	if ingress == egress {
+		t.Fatalf("expected same-named Meters with different labels to be distinct instances, got the same one")
+	}
+
+	ingress.Mark(10)
+	egress.Mark(1)
+	if ingress.Count() == egress.Count() {
+		t.Errorf("expected marking one Meter to not affect the other, both report %d", ingress.Count())
+	}
+
+	meters := registerer.Meters()
+	if len(meters) != 2 {
+		t.Fatalf("expected 2 distinct registered Meters, got %d: %v", len(meters), meters)
+	}
+	for m, labeled := range meters {
+		if labeled.Name != "xgress.rx.bytesrate" {
+			t.Errorf("expected LabeledMetric to keep the unqualified name, got %q", labeled.Name)
+		}
+		if m.Count() != 10 && m.Count() != 1 {
+			t.Errorf("expected registered Meter to be one of the two marked instances, got count %d", m.Count())
+		}
+	}
+}
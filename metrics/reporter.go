@@ -0,0 +1,59 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+// MeterSnapshot is a point-in-time view of a Meter.
+type MeterSnapshot struct {
+	Name   string
+	Labels map[string]string
+	Count  int64
+	Rate1  float64
+	Rate5  float64
+	Rate15 float64
+}
+
+// HistogramSnapshot is a point-in-time view of a Histogram, including the quantiles a Reporter is expected to
+// care about.
+type HistogramSnapshot struct {
+	Name        string
+	Labels      map[string]string
+	Count       int64
+	Sum         int64
+	Percentiles map[float64]float64
+}
+
+// IntervalCounterSnapshot is a point-in-time view of an IntervalCounter's current bucket, keyed by session id.
+type IntervalCounterSnapshot struct {
+	Name    string
+	Labels  map[string]string
+	Buckets map[string]uint64
+}
+
+// Snapshot is a point-in-time view of every metric a Reporter might want to ship off-box, keyed by seriesKey(name,
+// labels) so that same-named metrics distinguished only by their label set (per-link, ingress/egress, ...) don't
+// collapse onto a single entry. Use each entry's Name field, not the map key, to get the metric's display name.
+type Snapshot struct {
+	Meters           map[string]MeterSnapshot
+	Histograms       map[string]HistogramSnapshot
+	IntervalCounters map[string]IntervalCounterSnapshot
+}
+
+// Reporter ships a Snapshot off box. A Reporter should not block metric collection for other reporters, so
+// slow I/O (network publishes, etc.) belongs behind buffering internal to the implementation.
+type Reporter interface {
+	Report(snapshot Snapshot) error
+}
@@ -0,0 +1,99 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+// Package kafka reports metrics.Snapshots to Kafka, batching publishes through a sarama async producer so a
+// slow broker never blocks metric collection.
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	"github.com/michaelquigley/pfxlog"
+	"github.com/netfoundry/ziti-foundation/metrics"
+)
+
+// Config controls how the reporter connects to Kafka and routes metric families to topics.
+type Config struct {
+	Brokers []string
+	// Topic maps a metric name to the topic it should be published on. A nil Topic publishes everything to
+	// "metrics".
+	Topic func(metricName string) string
+	// Producer overrides the sarama producer config. A nil Producer uses a WaitForLocal, fire-and-forget config.
+	Producer *sarama.Config
+}
+
+type reporter struct {
+	producer sarama.AsyncProducer
+	topic    func(string) string
+}
+
+// NewReporter creates a Reporter which publishes every metric in a Snapshot as its own Kafka message, keyed by
+// metric name so consumers can partition by metric family.
+func NewReporter(config Config) (metrics.Reporter, error) {
+	producerConfig := config.Producer
+	if producerConfig == nil {
+		producerConfig = sarama.NewConfig()
+		producerConfig.Producer.RequiredAcks = sarama.WaitForLocal
+		producerConfig.Producer.Return.Successes = false
+	}
+
+	producer, err := sarama.NewAsyncProducer(config.Brokers, producerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka async producer: %w", err)
+	}
+
+	go func() {
+		for err := range producer.Errors() {
+			pfxlog.Logger().Errorf("Failed to publish metrics to kafka. Error: %v", err)
+		}
+	}()
+
+	topic := config.Topic
+	if topic == nil {
+		topic = func(string) string { return "metrics" }
+	}
+
+	return &reporter{producer: producer, topic: topic}, nil
+}
+
+func (r *reporter) Report(snapshot metrics.Snapshot) error {
+	for _, meter := range snapshot.Meters {
+		r.publish(meter.Name, meter)
+	}
+	for _, histogram := range snapshot.Histograms {
+		r.publish(histogram.Name, histogram)
+	}
+	for _, intervalCounter := range snapshot.IntervalCounters {
+		r.publish(intervalCounter.Name, intervalCounter)
+	}
+	return nil
+}
+
+func (r *reporter) publish(name string, value interface{}) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		pfxlog.Logger().Errorf("Failed to marshal metric [%v] for kafka. Error: %v", name, err)
+		return
+	}
+
+	r.producer.Input() <- &sarama.ProducerMessage{
+		Topic: r.topic(name),
+		Key:   sarama.StringEncoder(name),
+		Value: sarama.ByteEncoder(body),
+	}
+}
@@ -0,0 +1,148 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+// Package otlp reports metrics.Snapshots over OTLP/gRPC: Meters become Sums, Histograms become per-quantile
+// Gauges plus a count Sum (HistogramSnapshot only carries fixed percentiles, not real bucket boundaries, so
+// there's no bucket distribution to build a genuine ExponentialHistogram from), and IntervalCounters become
+// Sums with one data point per bucket, carrying a session_id data point attribute. session_id is not set as a
+// resource attribute: every export uses resource.Default() as its Resource.
+package otlp
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/netfoundry/ziti-foundation/metrics"
+)
+
+// Config controls where the reporter sends OTLP metrics and how export calls are bounded.
+type Config struct {
+	Endpoint      string
+	ExportTimeout time.Duration
+}
+
+type reporter struct {
+	exporter *otlpmetricgrpc.Exporter
+	timeout  time.Duration
+}
+
+// NewReporter creates a Reporter which exports each Snapshot as a single OTLP ResourceMetrics payload.
+func NewReporter(ctx context.Context, config Config) (metrics.Reporter, error) {
+	exporter, err := otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithEndpoint(config.Endpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := config.ExportTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &reporter{exporter: exporter, timeout: timeout}, nil
+}
+
+func (r *reporter) Report(snapshot metrics.Snapshot) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	now := time.Now()
+	metricsOut := make([]metricdata.Metrics, 0, len(snapshot.Meters)+len(snapshot.Histograms)+len(snapshot.IntervalCounters))
+
+	for _, meter := range snapshot.Meters {
+		metricsOut = append(metricsOut, metricdata.Metrics{
+			Name: meter.Name,
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{{
+					Attributes: attribute.NewSet(labelSet(meter.Labels)...),
+					Time:       now,
+					Value:      meter.Count,
+				}},
+			},
+		})
+	}
+
+	for _, histogram := range snapshot.Histograms {
+		for quantile, value := range histogram.Percentiles {
+			attrs := labelSet(histogram.Labels)
+			attrs = append(attrs, attribute.Float64("quantile", quantile))
+			metricsOut = append(metricsOut, metricdata.Metrics{
+				Name: histogram.Name,
+				Data: metricdata.Gauge[float64]{
+					DataPoints: []metricdata.DataPoint[float64]{{
+						Attributes: attribute.NewSet(attrs...),
+						Time:       now,
+						Value:      value,
+					}},
+				},
+			})
+		}
+		metricsOut = append(metricsOut, metricdata.Metrics{
+			Name: histogram.Name + ".count",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{{
+					Attributes: attribute.NewSet(labelSet(histogram.Labels)...),
+					Time:       now,
+					Value:      histogram.Count,
+				}},
+			},
+		})
+	}
+
+	for _, intervalCounter := range snapshot.IntervalCounters {
+		for sessionId, value := range intervalCounter.Buckets {
+			attrs := labelSet(intervalCounter.Labels)
+			attrs = append(attrs, attribute.String("session_id", sessionId))
+			metricsOut = append(metricsOut, metricdata.Metrics{
+				Name: intervalCounter.Name,
+				Data: metricdata.Sum[int64]{
+					Temporality: metricdata.DeltaTemporality,
+					IsMonotonic: true,
+					DataPoints: []metricdata.DataPoint[int64]{{
+						Attributes: attribute.NewSet(attrs...),
+						Time:       now,
+						Value:      int64(value),
+					}},
+				},
+			})
+		}
+	}
+
+	resourceMetrics := &metricdata.ResourceMetrics{
+		Resource: resource.Default(),
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Metrics: metricsOut,
+		}},
+	}
+
+	return r.exporter.Export(ctx, resourceMetrics)
+}
+
+func labelSet(labels map[string]string) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(labels))
+	for k, v := range labels {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	return attrs
+}
@@ -0,0 +1,42 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package otlp
+
+import "testing"
+
+func TestLabelSetConvertsEveryLabel(t *testing.T) {
+	attrs := labelSet(map[string]string{"link_id": "abc", "originator": "ingress"})
+
+	if len(attrs) != 2 {
+		t.Fatalf("expected 2 attributes, got %d: %v", len(attrs), attrs)
+	}
+
+	seen := make(map[string]string, len(attrs))
+	for _, attr := range attrs {
+		seen[string(attr.Key)] = attr.Value.AsString()
+	}
+	if seen["link_id"] != "abc" || seen["originator"] != "ingress" {
+		t.Errorf("expected link_id=abc and originator=ingress, got %v", seen)
+	}
+}
+
+func TestLabelSetHandlesEmptyLabels(t *testing.T) {
+	attrs := labelSet(nil)
+	if len(attrs) != 0 {
+		t.Errorf("expected no attributes for a nil label set, got %v", attrs)
+	}
+}
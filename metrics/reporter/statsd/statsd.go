@@ -0,0 +1,102 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+// Package statsd reports metrics.Snapshots to a StatsD/dogstatsd daemon over UDP, using dogstatsd's
+// "#tag:value" tag extension to carry labels.
+package statsd
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/netfoundry/ziti-foundation/metrics"
+)
+
+// Config controls where the reporter sends StatsD datagrams.
+type Config struct {
+	Address string // host:port of the statsd/dogstatsd daemon
+	Prefix  string // optional prefix prepended to every metric name
+}
+
+type reporter struct {
+	conn   net.Conn
+	prefix string
+}
+
+// NewReporter creates a Reporter which writes one UDP datagram per Snapshot, newline-delimited, dogstatsd-style.
+func NewReporter(config Config) (metrics.Reporter, error) {
+	conn, err := net.Dial("udp", config.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial statsd at %v: %w", config.Address, err)
+	}
+	return &reporter{conn: conn, prefix: config.Prefix}, nil
+}
+
+func (r *reporter) Report(snapshot metrics.Snapshot) error {
+	var buf strings.Builder
+
+	for _, meter := range snapshot.Meters {
+		r.writeGauge(&buf, meter.Name+".rate1m", meter.Labels, meter.Rate1)
+		r.writeGauge(&buf, meter.Name+".rate5m", meter.Labels, meter.Rate5)
+		r.writeGauge(&buf, meter.Name+".rate15m", meter.Labels, meter.Rate15)
+		r.writeCounter(&buf, meter.Name+".total", meter.Labels, float64(meter.Count))
+	}
+
+	for _, histogram := range snapshot.Histograms {
+		for quantile, value := range histogram.Percentiles {
+			r.writeGauge(&buf, fmt.Sprintf("%s.p%g", histogram.Name, quantile*100), histogram.Labels, value)
+		}
+		r.writeCounter(&buf, histogram.Name+".count", histogram.Labels, float64(histogram.Count))
+	}
+
+	for _, intervalCounter := range snapshot.IntervalCounters {
+		for sessionId, value := range intervalCounter.Buckets {
+			labels := make(map[string]string, len(intervalCounter.Labels)+1)
+			for k, v := range intervalCounter.Labels {
+				labels[k] = v
+			}
+			labels["session_id"] = sessionId
+			r.writeCounter(&buf, intervalCounter.Name+".total", labels, float64(value))
+		}
+	}
+
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	_, err := r.conn.Write([]byte(buf.String()))
+	return err
+}
+
+func (r *reporter) writeGauge(buf *strings.Builder, name string, labels map[string]string, value float64) {
+	fmt.Fprintf(buf, "%s%s:%f|g%s\n", r.prefix, name, value, tags(labels))
+}
+
+func (r *reporter) writeCounter(buf *strings.Builder, name string, labels map[string]string, value float64) {
+	fmt.Fprintf(buf, "%s%s:%f|c%s\n", r.prefix, name, value, tags(labels))
+}
+
+func tags(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+":"+v)
+	}
+	return "|#" + strings.Join(pairs, ",")
+}
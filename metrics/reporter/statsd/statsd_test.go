@@ -0,0 +1,52 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package statsd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTagsOmitsHashWhenEmpty(t *testing.T) {
+	if got := tags(nil); got != "" {
+		t.Errorf("expected no tag suffix for an empty label set, got %q", got)
+	}
+}
+
+func TestTagsFormatsDogstatsdStyle(t *testing.T) {
+	got := tags(map[string]string{"link_id": "abc"})
+	want := "|#link_id:abc"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestWriteGaugeAndCounterFormatDatagramLines(t *testing.T) {
+	r := &reporter{prefix: "ziti."}
+
+	var buf strings.Builder
+	r.writeGauge(&buf, "link.latency.p99", map[string]string{"link_id": "abc"}, 12.5)
+	r.writeCounter(&buf, "usage.fabric.rx.total", nil, 7)
+
+	out := buf.String()
+	if !strings.Contains(out, "ziti.link.latency.p99:12.500000|g|#link_id:abc\n") {
+		t.Errorf("expected a dogstatsd gauge line with the prefix and tag applied, got:\n%s", out)
+	}
+	if !strings.Contains(out, "ziti.usage.fabric.rx.total:7.000000|c\n") {
+		t.Errorf("expected a dogstatsd counter line with no tag suffix, got:\n%s", out)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import (
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/michaelquigley/pfxlog"
+)
+
+// MetricFilter selects which metrics a Reporter receives, by name. A nil Include matches every metric; a nil
+// Exclude excludes none. This lets a router send high-cardinality per-link/per-session metrics to one reporter
+// while sending only aggregates to another.
+type MetricFilter struct {
+	Include *regexp.Regexp
+	Exclude *regexp.Regexp
+}
+
+func (f *MetricFilter) matches(name string) bool {
+	if f == nil {
+		return true
+	}
+	if f.Include != nil && !f.Include.MatchString(name) {
+		return false
+	}
+	if f.Exclude != nil && f.Exclude.MatchString(name) {
+		return false
+	}
+	return true
+}
+
+// ReporterManager periodically snapshots a Registerer's metrics and fans the filtered result out to N
+// independently-scheduled Reporters.
+type ReporterManager struct {
+	registerer Registerer
+	lock       sync.Mutex
+	entries    []*reporterEntry
+}
+
+type reporterEntry struct {
+	reporter Reporter
+	filter   *MetricFilter
+	stop     chan struct{}
+}
+
+// NewReporterManager creates a ReporterManager which reads metrics from the given Registerer.
+func NewReporterManager(registerer Registerer) *ReporterManager {
+	return &ReporterManager{registerer: registerer}
+}
+
+// AddReporter registers a Reporter to receive a filtered Snapshot every flushInterval. A nil filter matches
+// every metric. The reporter's flush loop runs until the ReporterManager is stopped.
+func (m *ReporterManager) AddReporter(reporter Reporter, flushInterval time.Duration, filter *MetricFilter) {
+	entry := &reporterEntry{reporter: reporter, filter: filter, stop: make(chan struct{})}
+
+	m.lock.Lock()
+	m.entries = append(m.entries, entry)
+	m.lock.Unlock()
+
+	go m.run(entry, flushInterval)
+}
+
+func (m *ReporterManager) run(entry *reporterEntry, flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := entry.reporter.Report(m.snapshot(entry.filter)); err != nil {
+				pfxlog.Logger().Errorf("Failed to report metrics snapshot. Error: %v", err)
+			}
+		case <-entry.stop:
+			return
+		}
+	}
+}
+
+func (m *ReporterManager) snapshot(filter *MetricFilter) Snapshot {
+	snapshot := Snapshot{
+		Meters:           make(map[string]MeterSnapshot),
+		Histograms:       make(map[string]HistogramSnapshot),
+		IntervalCounters: make(map[string]IntervalCounterSnapshot),
+	}
+
+	for meter, labeled := range m.registerer.Meters() {
+		if !filter.matches(labeled.Name) {
+			continue
+		}
+		snapshot.Meters[seriesKey(labeled.Name, labeled.Labels)] = MeterSnapshot{
+			Name:   labeled.Name,
+			Labels: labeled.Labels,
+			Count:  meter.Count(),
+			Rate1:  meter.Rate1(),
+			Rate5:  meter.Rate5(),
+			Rate15: meter.Rate15(),
+		}
+	}
+
+	for histogram, labeled := range m.registerer.Histograms() {
+		if !filter.matches(labeled.Name) {
+			continue
+		}
+		snapshot.Histograms[seriesKey(labeled.Name, labeled.Labels)] = HistogramSnapshot{
+			Name:   labeled.Name,
+			Labels: labeled.Labels,
+			Count:  histogram.Count(),
+			Sum:    histogram.Sum(),
+			Percentiles: map[float64]float64{
+				0.5:  histogram.Percentile(0.5),
+				0.9:  histogram.Percentile(0.9),
+				0.99: histogram.Percentile(0.99),
+			},
+		}
+	}
+
+	for ic, labeled := range m.registerer.IntervalCounters() {
+		if !filter.matches(labeled.Name) {
+			continue
+		}
+		snapshot.IntervalCounters[seriesKey(labeled.Name, labeled.Labels)] = IntervalCounterSnapshot{
+			Name:    labeled.Name,
+			Labels:  labeled.Labels,
+			Buckets: ic.Current(),
+		}
+	}
+
+	return snapshot
+}
+
+// Stop halts every registered reporter's flush loop.
+func (m *ReporterManager) Stop() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, entry := range m.entries {
+		close(entry.stop)
+	}
+}
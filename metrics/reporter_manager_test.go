@@ -0,0 +1,83 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestMetricFilterNilMatchesEverything(t *testing.T) {
+	var f *MetricFilter
+	if !f.matches("anything") {
+		t.Errorf("expected a nil filter to match every metric name")
+	}
+}
+
+func TestMetricFilterIncludeExclude(t *testing.T) {
+	f := &MetricFilter{
+		Include: regexp.MustCompile(`^usage\.`),
+		Exclude: regexp.MustCompile(`\.xgress\.`),
+	}
+
+	if !f.matches("usage.fabric.rx") {
+		t.Errorf("expected usage.fabric.rx to match Include and not Exclude")
+	}
+	if f.matches("usage.xgress.rx") {
+		t.Errorf("expected usage.xgress.rx to be rejected by Exclude")
+	}
+	if f.matches("link.tx.bytesrate") {
+		t.Errorf("expected link.tx.bytesrate to be rejected for not matching Include")
+	}
+}
+
+func TestReporterManagerSnapshotAppliesFilterPerEntry(t *testing.T) {
+	registerer := NewRegisterer(NewRegistry())
+	registerer.Meter("usage.fabric.rx", nil).Mark(1)
+	registerer.Meter("link.tx.bytesrate", map[string]string{"link_id": "abc"}).Mark(1)
+
+	m := NewReporterManager(registerer)
+
+	unfiltered := m.snapshot(nil)
+	if len(unfiltered.Meters) != 2 {
+		t.Fatalf("expected an unfiltered snapshot to include both meters, got %d", len(unfiltered.Meters))
+	}
+
+	usageOnly := m.snapshot(&MetricFilter{Include: regexp.MustCompile(`^usage\.`)})
+	if len(usageOnly.Meters) != 1 {
+		t.Fatalf("expected a usage-only filter to include exactly 1 meter, got %d: %v", len(usageOnly.Meters), usageOnly.Meters)
+	}
+	for _, snap := range usageOnly.Meters {
+		if snap.Name != "usage.fabric.rx" {
+			t.Errorf("expected the surviving meter to be usage.fabric.rx, got %q", snap.Name)
+		}
+	}
+}
+
+func TestReporterManagerSnapshotKeysBySeriesNotBareName(t *testing.T) {
+	registerer := NewRegisterer(NewRegistry())
+	registerer.IntervalCounter("usage.xgress.rx", time.Minute, map[string]string{"originator": OriginatorIngress}).Update("s1", time.Now(), 1)
+	registerer.IntervalCounter("usage.xgress.rx", time.Minute, map[string]string{"originator": OriginatorEgress}).Update("s2", time.Now(), 1)
+
+	m := NewReporterManager(registerer)
+	snapshot := m.snapshot(nil)
+
+	if len(snapshot.IntervalCounters) != 2 {
+		t.Fatalf("expected ingress and egress usage.xgress.rx to remain distinct series, got %d: %v", len(snapshot.IntervalCounters), snapshot.IntervalCounters)
+	}
+}
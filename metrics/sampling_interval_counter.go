@@ -0,0 +1,246 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// SamplingMode selects the cardinality-control strategy a SamplingIntervalCounter uses to bound the number of
+// distinct sessions tracked per interval.
+type SamplingMode int
+
+const (
+	// HeavyHittersMode tracks the top-K sessions by volume using a Misra-Gries/Space-Saving sketch: fixed
+	// memory of K session->count entries; on overflow, every tracked counter is decremented and the (now
+	// smallest) entry is evicted to make room for the new session.
+	HeavyHittersMode SamplingMode = iota
+	// ReservoirMode keeps a uniform reservoir of K sessions per interval, scaling sampled values up to
+	// approximate the true total when the bucket is emitted.
+	ReservoirMode
+	// HardCapMode tracks every session up to a fixed count of K, folding anything past that into an
+	// "__other__" bucket rather than dropping it.
+	HardCapMode
+)
+
+// OtherSessionId is the bucket excess sessions are folded into under HardCapMode.
+const OtherSessionId = "__other__"
+
+// defaultSamplingIntervalCounterK is used when a SamplingIntervalCounterConfig doesn't set K.
+const defaultSamplingIntervalCounterK = 1000
+
+// SamplingIntervalCounterConfig controls how a SamplingIntervalCounter bounds cardinality.
+type SamplingIntervalCounterConfig struct {
+	Mode SamplingMode
+	// K bounds the number of distinct sessions tracked per interval. Defaults to 1000 if <= 0.
+	K int
+}
+
+// SamplingIntervalCounter is an IntervalCounter that bounds the number of distinct sessions tracked per
+// interval, so routers with millions of short-lived sessions don't blow up cardinality the way an unbounded
+// IntervalCounter.Update(sessionId, ...) would.
+//
+// Buckets normally roll over on the next Update once the interval has elapsed. A background ticker also rolls
+// the bucket on a timer, so a counter that stops receiving traffic mid-interval still emits its last partial
+// bucket instead of leaving a stale one in Current() forever.
+type SamplingIntervalCounter struct {
+	intervalSize time.Duration
+	config       SamplingIntervalCounterConfig
+
+	lock          sync.Mutex
+	intervalStart time.Time
+	current       map[string]uint64
+	seen          int
+	emitted       map[string]uint64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewSamplingIntervalCounter creates a SamplingIntervalCounter with the given interval size and cardinality
+// guardrail config, and starts its background flush timer.
+func NewSamplingIntervalCounter(intervalSize time.Duration, config SamplingIntervalCounterConfig) *SamplingIntervalCounter {
+	if config.K <= 0 {
+		config.K = defaultSamplingIntervalCounterK
+	}
+	c := &SamplingIntervalCounter{
+		intervalSize: intervalSize,
+		config:       config,
+		current:      make(map[string]uint64),
+		emitted:      make(map[string]uint64),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	go c.flushLoop()
+	return c
+}
+
+// flushLoop periodically rolls the current bucket even if no Update arrives to trigger it, so a counter that
+// goes idle mid-interval still emits its last, partial bucket instead of leaving Current() stuck on stale data.
+func (c *SamplingIntervalCounter) flushLoop() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.intervalSize)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			c.lock.Lock()
+			c.rollIfNeeded(now)
+			c.lock.Unlock()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// Update records value for sessionId at time when, applying this counter's cardinality guardrail.
+func (c *SamplingIntervalCounter) Update(sessionId string, when time.Time, value uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.rollIfNeeded(when)
+
+	switch c.config.Mode {
+	case ReservoirMode:
+		c.updateReservoir(sessionId, value)
+	case HardCapMode:
+		c.updateHardCap(sessionId, value)
+	default:
+		c.updateHeavyHitters(sessionId, value)
+	}
+}
+
+func (c *SamplingIntervalCounter) rollIfNeeded(when time.Time) {
+	bucketStart := when.Truncate(c.intervalSize)
+	if c.intervalStart.IsZero() {
+		c.intervalStart = bucketStart
+		return
+	}
+	if bucketStart.After(c.intervalStart) {
+		c.emitted = c.scaledCurrent()
+		c.current = make(map[string]uint64)
+		c.seen = 0
+		c.intervalStart = bucketStart
+	}
+}
+
+func (c *SamplingIntervalCounter) updateHeavyHitters(sessionId string, value uint64) {
+	if _, found := c.current[sessionId]; found {
+		c.current[sessionId] += value
+		return
+	}
+
+	if len(c.current) < c.config.K {
+		c.current[sessionId] = value
+		return
+	}
+
+	var minSessionId string
+	var minValue uint64
+	for sid, v := range c.current {
+		if minSessionId == "" || v < minValue {
+			minSessionId, minValue = sid, v
+		}
+	}
+
+	for sid, v := range c.current {
+		if v <= minValue {
+			delete(c.current, sid)
+		} else {
+			c.current[sid] = v - minValue
+		}
+	}
+	c.current[sessionId] = value
+}
+
+func (c *SamplingIntervalCounter) updateReservoir(sessionId string, value uint64) {
+	if _, found := c.current[sessionId]; found {
+		c.current[sessionId] += value
+		return
+	}
+
+	c.seen++
+	if len(c.current) < c.config.K {
+		c.current[sessionId] = value
+		return
+	}
+
+	if n := rand.Intn(c.seen); n < c.config.K {
+		victimIndex := 0
+		for sid := range c.current {
+			if victimIndex == n {
+				delete(c.current, sid)
+				break
+			}
+			victimIndex++
+		}
+		c.current[sessionId] = value
+	}
+}
+
+func (c *SamplingIntervalCounter) updateHardCap(sessionId string, value uint64) {
+	if _, found := c.current[sessionId]; found {
+		c.current[sessionId] += value
+		return
+	}
+	if len(c.current) < c.config.K {
+		c.current[sessionId] = value
+		return
+	}
+	c.current[OtherSessionId] += value
+}
+
+// scaledCurrent returns a copy of the current interval's bucket, scaling sampled values up under ReservoirMode
+// so the emitted total approximates what an unbounded counter would have reported.
+func (c *SamplingIntervalCounter) scaledCurrent() map[string]uint64 {
+	result := make(map[string]uint64, len(c.current))
+
+	if c.config.Mode != ReservoirMode || c.seen <= c.config.K {
+		for sid, v := range c.current {
+			result[sid] = v
+		}
+		return result
+	}
+
+	scale := float64(c.seen) / float64(c.config.K)
+	for sid, v := range c.current {
+		result[sid] = uint64(float64(v) * scale)
+	}
+	return result
+}
+
+// Current returns the most recently completed interval's bucket, session id to accumulated value.
+func (c *SamplingIntervalCounter) Current() map[string]uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	result := make(map[string]uint64, len(c.emitted))
+	for sid, v := range c.emitted {
+		result[sid] = v
+	}
+	return result
+}
+
+// Dispose stops the background flush timer. It blocks until the flush loop has exited.
+func (c *SamplingIntervalCounter) Dispose() {
+	close(c.stop)
+	<-c.done
+}
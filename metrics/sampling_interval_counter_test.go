@@ -0,0 +1,109 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSamplingIntervalCounterHeavyHittersEvictsSmallest(t *testing.T) {
+	c := NewSamplingIntervalCounter(time.Hour, SamplingIntervalCounterConfig{Mode: HeavyHittersMode, K: 2})
+	defer c.Dispose()
+
+	start := time.Now()
+	c.Update("a", start, 10)
+	c.Update("b", start, 1)
+	// K is already at capacity; "c" should evict the current smallest ("b") rather than growing past K.
+	c.Update("c", start, 5)
+
+	if len(c.current) > 2 {
+		t.Fatalf("expected at most K=2 tracked sessions, got %d: %v", len(c.current), c.current)
+	}
+	if _, found := c.current["b"]; found {
+		t.Errorf("expected smallest session %q to be evicted, still present: %v", "b", c.current)
+	}
+	if _, found := c.current["a"]; !found {
+		t.Errorf("expected largest session %q to survive eviction: %v", "a", c.current)
+	}
+}
+
+func TestSamplingIntervalCounterReservoirScalesUpOnEmit(t *testing.T) {
+	c := NewSamplingIntervalCounter(time.Hour, SamplingIntervalCounterConfig{Mode: ReservoirMode, K: 1})
+	defer c.Dispose()
+
+	intervalStart := time.Now().Truncate(time.Hour)
+	c.Update("a", intervalStart, 10)
+	// "seen" exceeds K once "b" arrives, exercising the reservoir's random-replacement path.
+	c.Update("b", intervalStart, 10)
+
+	// Roll into the next interval to force scaledCurrent() to run.
+	c.Update("z", intervalStart.Add(time.Hour), 1)
+
+	var total uint64
+	for _, v := range c.Current() {
+		total += v
+	}
+	if total != 20 {
+		t.Errorf("expected scaled-up total of 20 (2 sessions seen, 1 kept, value 10 scaled by 2), got %d", total)
+	}
+}
+
+func TestSamplingIntervalCounterHardCapFoldsExcessIntoOther(t *testing.T) {
+	c := NewSamplingIntervalCounter(time.Hour, SamplingIntervalCounterConfig{Mode: HardCapMode, K: 1})
+	defer c.Dispose()
+
+	now := time.Now()
+	c.Update("a", now, 10)
+	c.Update("b", now, 5)
+	c.Update("c", now, 3)
+
+	if c.current["a"] != 10 {
+		t.Errorf("expected tracked session %q to keep its own value, got %v", "a", c.current)
+	}
+	if c.current[OtherSessionId] != 8 {
+		t.Errorf("expected excess sessions folded into %q totaling 8, got %d", OtherSessionId, c.current[OtherSessionId])
+	}
+}
+
+func TestSamplingIntervalCounterFlushesOnTimerWithoutFurtherUpdates(t *testing.T) {
+	intervalSize := 20 * time.Millisecond
+	c := NewSamplingIntervalCounter(intervalSize, SamplingIntervalCounterConfig{Mode: HeavyHittersMode, K: 10})
+	defer c.Dispose()
+
+	c.Update("a", time.Now(), 42)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if v, ok := c.Current()["a"]; ok && v == 42 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected background flush to emit the last interval's bucket without a further Update, got %v", c.Current())
+}
+
+func TestSamplingIntervalCounterDisposeStopsFlushLoop(t *testing.T) {
+	c := NewSamplingIntervalCounter(time.Millisecond, SamplingIntervalCounterConfig{})
+	c.Dispose()
+
+	select {
+	case <-c.done:
+	default:
+		t.Fatalf("expected flush loop to have exited after Dispose")
+	}
+}
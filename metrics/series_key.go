@@ -0,0 +1,44 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sort"
+	"strings"
+)
+
+// seriesKey deterministically combines a metric name with its label set into a single string that identifies
+// one label-distinguished series, e.g. seriesKey("link.tx.bytesrate", map[string]string{"link_id": "abc"})
+// => `link.tx.bytesrate{link_id="abc"}`. Used both to give same-named-but-differently-labeled metrics distinct
+// identity in the underlying Registry, and to key Snapshot maps without collapsing series.
+func seriesKey(name string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+`="`+labels[k]+`"`)
+	}
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
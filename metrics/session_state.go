@@ -0,0 +1,109 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package metrics
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Session flag bits tracked in XgressDetail.Flags.
+const (
+	SessionFlagTxSeen uint32 = 1 << iota
+	SessionFlagRxSeen
+)
+
+// Originator values recorded in sessionState.originator, matching the "originator" label xgressPeekHandler
+// registers its metrics under.
+const (
+	OriginatorIngress = "ingress"
+	OriginatorEgress  = "egress"
+)
+
+// defaultSessionLRUCapacity bounds how many sessions a peek handler tracks detail for at once, so routers with
+// millions of short-lived sessions don't grow this state unbounded.
+const defaultSessionLRUCapacity = 10000
+
+// sessionState is the per-session detail a peek handler tracks between Inspect calls. Sequence counters are
+// locally-observed message counts, widened to uint64 so they don't wrap on long-lived, high-throughput sessions.
+// originator records which side of the circuit this session was seen on ("ingress"/"egress"); it is only
+// meaningful for handlers that distinguish originators (see xgressPeekHandler) and left blank otherwise.
+type sessionState struct {
+	txSeq      uint64
+	rxSeq      uint64
+	flags      uint32
+	lastTxAt   time.Time
+	lastRxAt   time.Time
+	originator string
+}
+
+type sessionLRUEntry struct {
+	sessionId string
+	state     *sessionState
+}
+
+// sessionLRU is a fixed-capacity, least-recently-used cache of sessionState, keyed by session id.
+type sessionLRU struct {
+	capacity int
+	lock     sync.Mutex
+	elements map[string]*list.Element
+	order    *list.List
+}
+
+func newSessionLRU(capacity int) *sessionLRU {
+	return &sessionLRU{
+		capacity: capacity,
+		elements: make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// update applies fn to the sessionState for sessionId, creating one and evicting the least-recently-used
+// session if the cache is at capacity.
+func (c *sessionLRU) update(sessionId string, fn func(*sessionState)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, found := c.elements[sessionId]
+	if !found {
+		elem = c.order.PushFront(&sessionLRUEntry{sessionId: sessionId, state: &sessionState{}})
+		c.elements[sessionId] = elem
+
+		if c.order.Len() > c.capacity {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.elements, oldest.Value.(*sessionLRUEntry).sessionId)
+		}
+	} else {
+		c.order.MoveToFront(elem)
+	}
+
+	fn(elem.Value.(*sessionLRUEntry).state)
+}
+
+// get returns a copy of the tracked sessionState for sessionId, if present.
+func (c *sessionLRU) get(sessionId string) (sessionState, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, found := c.elements[sessionId]
+	if !found {
+		return sessionState{}, false
+	}
+	return *elem.Value.(*sessionLRUEntry).state, true
+}